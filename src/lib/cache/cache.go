@@ -0,0 +1,80 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is the interface for cache library.
+type Cache interface {
+	// Contains returns true if key exists.
+	Contains(ctx context.Context, key string) bool
+
+	// Fetch retrieves the cached value for key and populates value, value
+	// should be a pointer.
+	Fetch(ctx context.Context, key string, value interface{}) error
+
+	// Save caches the value under key for the given expiration, the default
+	// expiration time will be used if the expiration is not specified.
+	Save(ctx context.Context, key string, value interface{}, expiration ...time.Duration) error
+
+	// SaveWithTags caches the value under key like Save, and additionally
+	// associates key with every tag in tags so the entry can later be
+	// invalidated by tag via InvalidateTag without knowing the key itself.
+	SaveWithTags(ctx context.Context, key string, value interface{}, tags []string, expiration ...time.Duration) error
+
+	// Delete the cached value of the key.
+	Delete(ctx context.Context, key string) error
+
+	// Keys returns all the keys that match prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+
+	// InvalidateTag deletes every key that was associated with tag via
+	// SaveWithTags, along with the tag itself, and returns the keys that
+	// were deleted so callers with a second layer of their own (e.g. an L1
+	// in front of this Cache) can evict/propagate each one individually.
+	InvalidateTag(ctx context.Context, tag string) ([]string, error)
+
+	// Untag removes key's membership from each tag in tags without
+	// deleting key's cached value or the other members of those tags. Use
+	// this instead of InvalidateTag when a tag is shared by more entries
+	// than the one being retired, e.g. dropping a single deleted project
+	// out of a shared "owner:<uid>" tag without evicting every other
+	// project cached under that owner.
+	Untag(ctx context.Context, key string, tags []string) error
+
+	// Ping checks if the cache is available.
+	Ping(ctx context.Context) error
+}
+
+// Notifier is implemented by Cache backends that support cross-process
+// pub/sub. It's used to keep a per-replica L1 cache coherent: a replica that
+// invalidates an entry publishes it so every other replica's L1 can evict
+// the same key instead of waiting out its TTL.
+type Notifier interface {
+	// Publish broadcasts message on channel to every subscriber.
+	Publish(ctx context.Context, channel, message string) error
+	// Subscribe calls handler for every message published on channel until
+	// ctx is done. It blocks, so callers run it in its own goroutine.
+	Subscribe(ctx context.Context, channel string, handler func(message string)) error
+}
+
+// Default returns the default cache implementation configured for this
+// Harbor instance.
+func Default() Cache {
+	return defaultCache()
+}
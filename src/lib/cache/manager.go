@@ -0,0 +1,112 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goharbor/harbor/src/lib/redis/pool"
+)
+
+// Backend names the Cache implementation a Namespace is served by.
+const (
+	// BackendRedis stores entries in the shared redis instance, visible to
+	// every replica. It's the right choice for state that must stay
+	// coherent across the cluster.
+	BackendRedis = "redis"
+	// BackendMemory stores entries in a bounded in-process LRU. It's
+	// cheaper and avoids the redis round-trip, at the cost of every
+	// replica keeping its own copy, so it only suits data that tolerates
+	// short-lived staleness or per-replica divergence.
+	BackendMemory = "memory"
+)
+
+// Namespace configures the Cache returned for a single named resource, e.g.
+// "project" or "repository". Namespaces are configured independently so
+// operators can route large/rarely-changed resources to BackendMemory while
+// keeping shared multi-instance state in BackendRedis.
+type Namespace struct {
+	// Backend selects the underlying implementation, one of the Backend*
+	// constants. Defaults to BackendRedis if empty.
+	Backend string
+	// Prefix is prepended to every key written under this namespace, so
+	// namespaces sharing a single redis instance never collide.
+	Prefix string
+	// TTL is the default expiration applied to entries saved without an
+	// explicit one.
+	TTL time.Duration
+	// Capacity bounds the number of entries BackendMemory keeps resident;
+	// ignored by BackendRedis.
+	Capacity int
+}
+
+// Manager hands out a Cache per named namespace, each configured
+// independently instead of every caller reaching for the single global
+// Default() cache.
+type Manager interface {
+	// Cache returns the Cache configured for namespace, building it lazily
+	// from its registered Namespace on first use. An unregistered
+	// namespace falls back to the shared Default() cache.
+	Cache(namespace string) Cache
+}
+
+type manager struct {
+	namespaces map[string]Namespace
+
+	mu     sync.Mutex
+	caches map[string]Cache
+}
+
+// NewManager returns a Manager that lazily builds one Cache per namespace
+// according to namespaces.
+func NewManager(namespaces map[string]Namespace) Manager {
+	return &manager{
+		namespaces: namespaces,
+		caches:     make(map[string]Cache),
+	}
+}
+
+func (m *manager) Cache(namespace string) Cache {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if c, ok := m.caches[namespace]; ok {
+		return c
+	}
+
+	c := m.build(m.namespaces[namespace])
+	m.caches[namespace] = c
+	return c
+}
+
+func (m *manager) build(ns Namespace) Cache {
+	switch ns.Backend {
+	case BackendMemory:
+		return newMemoryCache(ns.Capacity, ns.TTL)
+	case BackendRedis, "":
+		// an empty Backend defaults to BackendRedis, still honoring
+		// ns.Prefix and ns.TTL, per the Namespace doc comments.
+		c := Default()
+		if ns.TTL > 0 {
+			c = newRedisCache(pool.Client(), ns.TTL)
+		}
+		return newPrefixedCache(c, ns.Prefix)
+	default:
+		// unrecognized backend: behave like the pre-Manager global cache
+		// so existing callers keep working untouched.
+		return Default()
+	}
+}
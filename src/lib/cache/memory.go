@@ -0,0 +1,173 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// defaultMemoryCapacity bounds a namespace's in-process LRU when the
+// Namespace didn't specify one, so a misconfigured namespace can't grow
+// unbounded.
+const defaultMemoryCapacity = 10000
+
+// ErrNotFound is returned by Fetch when key isn't cached, mirroring a redis
+// cache miss so callers can treat the two backends identically.
+var ErrNotFound = errors.New("cache: key not found")
+
+type memoryEntry struct {
+	raw       []byte
+	expiresAt time.Time
+}
+
+// memoryCache is an in-process, single-instance Cache backed by a bounded
+// LRU. It lets single-replica deployments drop the redis dependency for
+// namespaces that tolerate per-replica divergence.
+type memoryCache struct {
+	lru *lru.Cache[string, memoryEntry]
+
+	defaultTTL time.Duration
+
+	mu   sync.Mutex
+	tags map[string]map[string]struct{}
+}
+
+// NewMemory returns a standalone in-process Cache, e.g. for use as an L1
+// layer in front of a slower shared backend. capacity <= 0 falls back to
+// defaultMemoryCapacity.
+func NewMemory(capacity int, defaultTTL time.Duration) Cache {
+	return newMemoryCache(capacity, defaultTTL)
+}
+
+func newMemoryCache(capacity int, defaultTTL time.Duration) Cache {
+	if capacity <= 0 {
+		capacity = defaultMemoryCapacity
+	}
+
+	l, _ := lru.New[string, memoryEntry](capacity)
+	return &memoryCache{
+		lru:        l,
+		defaultTTL: defaultTTL,
+		tags:       make(map[string]map[string]struct{}),
+	}
+}
+
+func (c *memoryCache) Contains(_ context.Context, key string) bool {
+	e, ok := c.lru.Get(key)
+	return ok && !c.expired(e)
+}
+
+func (c *memoryCache) Fetch(_ context.Context, key string, value interface{}) error {
+	e, ok := c.lru.Get(key)
+	if !ok || c.expired(e) {
+		return ErrNotFound
+	}
+	return json.Unmarshal(e.raw, value)
+}
+
+func (c *memoryCache) Save(_ context.Context, key string, value interface{}, expiration ...time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.lru.Add(key, c.entry(raw, expiration...))
+	return nil
+}
+
+func (c *memoryCache) SaveWithTags(ctx context.Context, key string, value interface{}, tags []string, expiration ...time.Duration) error {
+	if err := c.Save(ctx, key, value, expiration...); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tag := range tags {
+		members, ok := c.tags[tag]
+		if !ok {
+			members = make(map[string]struct{})
+			c.tags[tag] = members
+		}
+		members[key] = struct{}{}
+	}
+	return nil
+}
+
+func (c *memoryCache) Delete(_ context.Context, key string) error {
+	c.lru.Remove(key)
+	return nil
+}
+
+func (c *memoryCache) Keys(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for _, key := range c.lru.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (c *memoryCache) InvalidateTag(_ context.Context, tag string) ([]string, error) {
+	c.mu.Lock()
+	members := c.tags[tag]
+	delete(c.tags, tag)
+	c.mu.Unlock()
+
+	keys := make([]string, 0, len(members))
+	for key := range members {
+		c.lru.Remove(key)
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Untag removes key from each tag in tags without deleting its cached value
+// or any other member of those tags.
+func (c *memoryCache) Untag(_ context.Context, key string, tags []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tag := range tags {
+		delete(c.tags[tag], key)
+	}
+	return nil
+}
+
+func (c *memoryCache) Ping(_ context.Context) error {
+	return nil
+}
+
+func (c *memoryCache) entry(raw []byte, expiration ...time.Duration) memoryEntry {
+	ttl := c.defaultTTL
+	if len(expiration) > 0 {
+		ttl = expiration[0]
+	}
+
+	e := memoryEntry{raw: raw}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl)
+	}
+	return e
+}
+
+func (c *memoryCache) expired(e memoryEntry) bool {
+	return !e.expiresAt.IsZero() && time.Now().After(e.expiresAt)
+}
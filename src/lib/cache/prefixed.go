@@ -0,0 +1,130 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// prefixedCache wraps a Cache and prepends prefix to every key, so several
+// namespaces can share one underlying backend (e.g. one redis instance)
+// without their keys colliding.
+type prefixedCache struct {
+	delegate Cache
+	prefix   string
+}
+
+func newPrefixedCache(delegate Cache, prefix string) Cache {
+	if prefix == "" {
+		return delegate
+	}
+	return &prefixedCache{delegate: delegate, prefix: prefix}
+}
+
+func (c *prefixedCache) key(key string) string {
+	return c.prefix + key
+}
+
+// unkey strips prefix back off a key the delegate returned, so callers of
+// this Cache only ever see the unprefixed keys they themselves used.
+func (c *prefixedCache) unkey(key string) string {
+	return strings.TrimPrefix(key, c.prefix)
+}
+
+func (c *prefixedCache) Contains(ctx context.Context, key string) bool {
+	return c.delegate.Contains(ctx, c.key(key))
+}
+
+func (c *prefixedCache) Fetch(ctx context.Context, key string, value interface{}) error {
+	return c.delegate.Fetch(ctx, c.key(key), value)
+}
+
+func (c *prefixedCache) Save(ctx context.Context, key string, value interface{}, expiration ...time.Duration) error {
+	return c.delegate.Save(ctx, c.key(key), value, expiration...)
+}
+
+func (c *prefixedCache) SaveWithTags(ctx context.Context, key string, value interface{}, tags []string, expiration ...time.Duration) error {
+	prefixedTags := make([]string, len(tags))
+	for i, tag := range tags {
+		prefixedTags[i] = c.key(tag)
+	}
+	return c.delegate.SaveWithTags(ctx, c.key(key), value, prefixedTags, expiration...)
+}
+
+func (c *prefixedCache) Delete(ctx context.Context, key string) error {
+	return c.delegate.Delete(ctx, c.key(key))
+}
+
+func (c *prefixedCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := c.delegate.Keys(ctx, c.key(prefix))
+	if err != nil {
+		return nil, err
+	}
+
+	unprefixed := make([]string, len(keys))
+	for i, key := range keys {
+		unprefixed[i] = c.unkey(key)
+	}
+	return unprefixed, nil
+}
+
+func (c *prefixedCache) InvalidateTag(ctx context.Context, tag string) ([]string, error) {
+	keys, err := c.delegate.InvalidateTag(ctx, c.key(tag))
+	if err != nil {
+		return nil, err
+	}
+
+	unprefixed := make([]string, len(keys))
+	for i, key := range keys {
+		unprefixed[i] = c.unkey(key)
+	}
+	return unprefixed, nil
+}
+
+func (c *prefixedCache) Untag(ctx context.Context, key string, tags []string) error {
+	prefixedTags := make([]string, len(tags))
+	for i, tag := range tags {
+		prefixedTags[i] = c.key(tag)
+	}
+	return c.delegate.Untag(ctx, c.key(key), prefixedTags)
+}
+
+func (c *prefixedCache) Ping(ctx context.Context) error {
+	return c.delegate.Ping(ctx)
+}
+
+// Publish implements Notifier by forwarding to the wrapped delegate, and
+// returns an error if the delegate doesn't implement Notifier; channels
+// aren't keys, so they're passed through unprefixed.
+func (c *prefixedCache) Publish(ctx context.Context, channel, message string) error {
+	n, ok := c.delegate.(Notifier)
+	if !ok {
+		return errors.New("cache: delegate does not support pub/sub")
+	}
+	return n.Publish(ctx, channel, message)
+}
+
+// Subscribe implements Notifier by forwarding to the wrapped delegate, and
+// returns an error if the delegate doesn't implement Notifier.
+func (c *prefixedCache) Subscribe(ctx context.Context, channel string, handler func(message string)) error {
+	n, ok := c.delegate.(Notifier)
+	if !ok {
+		return errors.New("cache: delegate does not support pub/sub")
+	}
+	return n.Subscribe(ctx, channel, handler)
+}
@@ -0,0 +1,70 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPrefixedCacheSaveKeysDeleteRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := newPrefixedCache(newMemoryCache(0, 0), "ns:")
+
+	if err := c.Save(ctx, "a", "value"); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	keys, err := c.Keys(ctx, "")
+	if err != nil {
+		t.Fatalf("Keys error: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("expected Keys to return the unprefixed key [\"a\"], got %v", keys)
+	}
+
+	for _, key := range keys {
+		if err := c.Delete(ctx, key); err != nil {
+			t.Fatalf("Delete(%q) error: %v", key, err)
+		}
+	}
+
+	var value string
+	if err := c.Fetch(ctx, "a", &value); err == nil {
+		t.Fatalf("expected key %q to be gone after Keys+Delete round trip, still fetched %q", "a", value)
+	}
+}
+
+func TestPrefixedCacheSaveWithTagsInvalidateTagRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := newPrefixedCache(newMemoryCache(0, 0), "ns:")
+
+	if err := c.SaveWithTags(ctx, "a", "value", []string{"tag1"}); err != nil {
+		t.Fatalf("SaveWithTags error: %v", err)
+	}
+
+	keys, err := c.InvalidateTag(ctx, "tag1")
+	if err != nil {
+		t.Fatalf("InvalidateTag error: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("expected InvalidateTag to return the unprefixed key [\"a\"], got %v", keys)
+	}
+
+	var value string
+	if err := c.Fetch(ctx, "a", &value); err == nil {
+		t.Fatalf("expected key %q to be evicted by InvalidateTag, still fetched %q", "a", value)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/goharbor/harbor/src/lib/log"
+	"github.com/goharbor/harbor/src/lib/redis/pool"
+)
+
+// tagKeyPrefix is prepended to a tag name to build the key of the redis set
+// that holds the members (cache keys) tagged with it.
+const tagKeyPrefix = "cache:tag:"
+
+// redisCache is the redis backed implementation of Cache.
+type redisCache struct {
+	client          *redis.Client
+	defaultLifetime time.Duration
+}
+
+func newRedisCache(client *redis.Client, defaultLifetime time.Duration) Cache {
+	return &redisCache{
+		client:          client,
+		defaultLifetime: defaultLifetime,
+	}
+}
+
+func (c *redisCache) Contains(ctx context.Context, key string) bool {
+	n, err := c.client.Exists(ctx, key).Result()
+	return err == nil && n > 0
+}
+
+func (c *redisCache) Fetch(ctx context.Context, key string, value interface{}) error {
+	raw, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, value)
+}
+
+func (c *redisCache) Save(ctx context.Context, key string, value interface{}, expiration ...time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, key, raw, c.expiration(expiration...)).Err()
+}
+
+// SaveWithTags saves value under key and, in the same pipeline, adds key to
+// the redis set backing each tag, mirroring the tagging support gocache
+// provides.
+func (c *redisCache) SaveWithTags(ctx context.Context, key string, value interface{}, tags []string, expiration ...time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, raw, c.expiration(expiration...))
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tagKey(tag), key)
+		}
+		return nil
+	})
+
+	return err
+}
+
+func (c *redisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, key).Err()
+}
+
+// keysScanCount is the COUNT hint passed to each SCAN call, not a hard cap on
+// the number of keys returned: Keys loops until the cursor comes back to 0,
+// accumulating every matching key across as many round-trips as it takes.
+const keysScanCount = 100
+
+// Keys returns every key matching prefix by cursor-walking the keyspace with
+// SCAN rather than KEYS, so a large keyspace doesn't block the single-
+// threaded redis server for the duration of the call.
+func (c *redisCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var (
+		keys   []string
+		cursor uint64
+	)
+	for {
+		batch, next, err := c.client.Scan(ctx, cursor, prefix+"*", keysScanCount).Result()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, batch...)
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return keys, nil
+}
+
+// InvalidateTag fetches the members of the tag's set and deletes them along
+// with the set itself in a single pipeline, so cleaning up a tag never costs
+// more than one extra round-trip regardless of how many keys it covers. It
+// returns the member keys that were deleted so a caller layering its own
+// cache (e.g. an L1) in front of this one can evict/propagate each of them.
+func (c *redisCache) InvalidateTag(ctx context.Context, tag string) ([]string, error) {
+	key := tagKey(tag)
+
+	members, err := c.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, c.client.Del(ctx, key).Err()
+	}
+
+	_, err = c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, members...)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// Untag removes key from the redis set backing each tag in tags, without
+// touching key's own cached value or any other member of those sets.
+func (c *redisCache) Untag(ctx context.Context, key string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			pipe.SRem(ctx, tagKey(tag), key)
+		}
+		return nil
+	})
+	return err
+}
+
+func (c *redisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// Publish implements Notifier.
+func (c *redisCache) Publish(ctx context.Context, channel, message string) error {
+	return c.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe implements Notifier. It blocks delivering messages to handler
+// until ctx is done or the subscription's channel is closed.
+func (c *redisCache) Subscribe(ctx context.Context, channel string, handler func(message string)) error {
+	sub := c.client.Subscribe(ctx, channel)
+	defer sub.Close()
+
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			handler(msg.Payload)
+		}
+	}
+}
+
+func (c *redisCache) expiration(expiration ...time.Duration) time.Duration {
+	if len(expiration) > 0 {
+		return expiration[0]
+	}
+	return c.defaultLifetime
+}
+
+func tagKey(tag string) string {
+	return tagKeyPrefix + tag
+}
+
+var (
+	defaultOnce      sync.Once
+	defaultCacheImpl Cache
+)
+
+// defaultCache lazily builds the singleton Cache backed by the shared redis
+// pool, so every caller of Default() observes the same tag sets.
+func defaultCache() Cache {
+	defaultOnce.Do(func() {
+		client := pool.Client()
+		defaultCacheImpl = newRedisCache(client, time.Hour)
+		log.Debugf("initialized default redis cache")
+	})
+	return defaultCacheImpl
+}
@@ -0,0 +1,27 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+// defaultListCacheExpireSeconds bounds how long a cached List/Count result
+// is trusted. It's much shorter than defaultCacheExpireHours because a
+// listing goes stale the moment any row starts/stops matching its query.
+const defaultListCacheExpireSeconds = 30
+
+// ListCacheExpireSeconds returns the TTL, in seconds, applied to cached
+// project List/Count results. Configurable via the
+// LIST_CACHE_EXPIRE_SECONDS env var / harbor.yml ini key.
+func ListCacheExpireSeconds() int {
+	return envInt("LIST_CACHE_EXPIRE_SECONDS", defaultListCacheExpireSeconds)
+}
@@ -0,0 +1,48 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// Defaults applied when the corresponding env var isn't set or isn't a
+// valid integer.
+const (
+	defaultCacheExpireHours = 24
+)
+
+// CacheExpireHours returns the number of hours a cached single-object entry
+// (e.g. a project) is kept before it expires. Configurable via the
+// CACHE_EXPIRE_HOURS env var / harbor.yml ini key.
+func CacheExpireHours() int {
+	return envInt("CACHE_EXPIRE_HOURS", defaultCacheExpireHours)
+}
+
+// envInt reads key from the environment, falling back to def if it's unset
+// or not a valid integer.
+func envInt(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,28 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+// defaultProjectL1CacheExpireSeconds bounds how long an L1 in-process
+// project cache entry is trusted before it's re-fetched from L2. It's kept
+// short since L1 staleness is only bounded by this TTL on replicas that
+// miss a pub/sub invalidation (e.g. during a redis reconnect).
+const defaultProjectL1CacheExpireSeconds = 10
+
+// ProjectL1CacheExpireSeconds returns the TTL, in seconds, applied to the
+// project cache Manager's L1 in-process layer. Configurable via the
+// PROJECT_L1_CACHE_EXPIRE_SECONDS env var / harbor.yml ini key.
+func ProjectL1CacheExpireSeconds() int {
+	return envInt("PROJECT_L1_CACHE_EXPIRE_SECONDS", defaultProjectL1CacheExpireSeconds)
+}
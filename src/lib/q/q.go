@@ -0,0 +1,58 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package q
+
+// Query defines the query conditions for listing/counting resources from
+// DAO methods.
+type Query struct {
+	// Keywords filters the result list by the "key: value" pairs it
+	// contains, one pair per column to filter on.
+	Keywords map[string]interface{}
+	// PageNumber is the page of the result set to fetch, starting at 1.
+	// A value of 0 means no pagination, i.e. fetch every matching row.
+	PageNumber int64
+	// PageSize is the number of rows per page.
+	PageSize int64
+	// Sorts specifies the sorting order applied to the result.
+	Sorts []*Sort
+	// Sorting is kept for backward compatibility with callers that still
+	// build the sort clause as a single comma separated string, e.g.
+	// "+name,-creation_time".
+	Sorting string
+	// NoCache bypasses any read-through cache in front of the DAO so the
+	// caller always sees authoritative results straight from the database.
+	// Admin tooling that just mutated data out-of-band should set this
+	// instead of waiting out the cache TTL.
+	NoCache bool
+}
+
+// Sort describes a single ORDER BY clause.
+type Sort struct {
+	// Key is the name of the field to sort by.
+	Key string
+	// DESC indicates sort order is descending if it is true.
+	DESC bool
+}
+
+// MustClone returns a non-nil *Query: a shallow copy of query if it isn't
+// nil, or an empty Query otherwise. Callers use it to avoid nil-checking query before
+// reading its fields.
+func MustClone(query *Query) *Query {
+	if query == nil {
+		return &Query{}
+	}
+	c := *query
+	return &c
+}
@@ -0,0 +1,73 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cached
+
+import (
+	"context"
+	"sync"
+
+	"github.com/goharbor/harbor/src/lib/errors"
+	"github.com/goharbor/harbor/src/lib/retry"
+)
+
+// OnProjectDeletedFunc is called after a project has been deleted so a
+// cached manager for another resource (repositories, artifacts, quotas,
+// robots, webhooks, members, ...) can flush whatever it cached that was
+// scoped to projectID.
+type OnProjectDeletedFunc func(ctx context.Context, projectID int64) error
+
+// InvalidationHub lets cached managers for other resources register
+// interest in cross-cutting events, such as a project deletion, without the
+// resource that raises the event needing to know its subscribers up front.
+type InvalidationHub struct {
+	mu              sync.RWMutex
+	onProjectDelete []OnProjectDeletedFunc
+}
+
+// DefaultInvalidationHub is the hub the project cache Manager fans out to
+// on Delete. Other cached managers register against it, typically from
+// their own constructor.
+var DefaultInvalidationHub = &InvalidationHub{}
+
+// OnProjectDeleted registers hook to run whenever a project is deleted.
+func (h *InvalidationHub) OnProjectDeleted(hook OnProjectDeletedFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onProjectDelete = append(h.onProjectDelete, hook)
+}
+
+// NotifyProjectDeleted runs every hook registered via OnProjectDeleted for
+// projectID. Each hook is retried independently via retry.Retry so a
+// transient error in one doesn't prevent the others from running, and every
+// hook's final error is aggregated into a single errors.Errors.
+func (h *InvalidationHub) NotifyProjectDeleted(ctx context.Context, projectID int64) error {
+	h.mu.RLock()
+	hooks := make([]OnProjectDeletedFunc, len(h.onProjectDelete))
+	copy(hooks, h.onProjectDelete)
+	h.mu.RUnlock()
+
+	var errs errors.Errors
+	for _, hook := range hooks {
+		hook := hook
+		if err := retry.Retry(func() error { return hook(ctx, projectID) }); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if errs.Len() > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cached
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestInvalidationHubNotifyProjectDeletedRunsEveryHook(t *testing.T) {
+	h := &InvalidationHub{}
+
+	var gotA, gotB int64
+	h.OnProjectDeleted(func(_ context.Context, projectID int64) error {
+		gotA = projectID
+		return nil
+	})
+	h.OnProjectDeleted(func(_ context.Context, projectID int64) error {
+		gotB = projectID
+		return nil
+	})
+
+	if err := h.NotifyProjectDeleted(context.Background(), 42); err != nil {
+		t.Fatalf("NotifyProjectDeleted error: %v", err)
+	}
+	if gotA != 42 || gotB != 42 {
+		t.Errorf("expected every registered hook to run with projectID 42, got %d and %d", gotA, gotB)
+	}
+}
+
+func TestInvalidationHubNotifyProjectDeletedAggregatesErrors(t *testing.T) {
+	h := &InvalidationHub{}
+
+	ranSecond := false
+	h.OnProjectDeleted(func(context.Context, int64) error {
+		return errors.New("boom")
+	})
+	h.OnProjectDeleted(func(context.Context, int64) error {
+		ranSecond = true
+		return nil
+	})
+
+	err := h.NotifyProjectDeleted(context.Background(), 1)
+	if err == nil {
+		t.Fatal("expected NotifyProjectDeleted to return the first hook's error")
+	}
+	if !ranSecond {
+		t.Error("expected the second hook to still run after the first failed")
+	}
+}
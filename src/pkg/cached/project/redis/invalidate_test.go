@@ -0,0 +1,72 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	libcache "github.com/goharbor/harbor/src/lib/cache"
+	"github.com/goharbor/harbor/src/pkg/project/models"
+)
+
+// fakeL2Cache is a minimal libcache.Cache stand-in whose InvalidateTag
+// returns a canned set of member keys, so InvalidateByTag's L1 fanout can be
+// tested without a real redis.
+type fakeL2Cache struct {
+	invalidateKeys []string
+}
+
+func (f *fakeL2Cache) Contains(context.Context, string) bool { return false }
+func (f *fakeL2Cache) Fetch(context.Context, string, interface{}) error {
+	return errors.New("fakeL2Cache: miss")
+}
+func (f *fakeL2Cache) Save(context.Context, string, interface{}, ...time.Duration) error { return nil }
+func (f *fakeL2Cache) SaveWithTags(context.Context, string, interface{}, []string, ...time.Duration) error {
+	return nil
+}
+func (f *fakeL2Cache) Delete(context.Context, string) error           { return nil }
+func (f *fakeL2Cache) Keys(context.Context, string) ([]string, error) { return nil, nil }
+func (f *fakeL2Cache) InvalidateTag(context.Context, string) ([]string, error) {
+	return f.invalidateKeys, nil
+}
+func (f *fakeL2Cache) Untag(context.Context, string, []string) error { return nil }
+func (f *fakeL2Cache) Ping(context.Context) error                    { return nil }
+
+func TestInvalidateByTagEvictsL1(t *testing.T) {
+	ctx := context.Background()
+
+	l1 := libcache.NewMemory(0, time.Hour)
+	if err := l1.Save(ctx, "project:id:1", &models.Project{ProjectID: 1}); err != nil {
+		t.Fatalf("seed L1 error: %v", err)
+	}
+
+	l2 := &fakeL2Cache{invalidateKeys: []string{"project:id:1"}}
+	mgr := &Manager{
+		client: func() libcache.Cache { return l2 },
+		l1:     l1,
+	}
+
+	if err := mgr.InvalidateByTag(ctx, "owner:1"); err != nil {
+		t.Fatalf("InvalidateByTag error: %v", err)
+	}
+
+	var p models.Project
+	if err := l1.Fetch(ctx, "project:id:1", &p); err == nil {
+		t.Fatalf("expected InvalidateByTag to evict the project from L1 too, but it's still cached")
+	}
+}
@@ -16,8 +16,15 @@ package redis
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
 	"time"
 
+	"golang.org/x/sync/singleflight"
+
 	"github.com/goharbor/harbor/src/common/utils"
 	libcache "github.com/goharbor/harbor/src/lib/cache"
 	"github.com/goharbor/harbor/src/lib/config"
@@ -30,6 +37,23 @@ import (
 	"github.com/goharbor/harbor/src/pkg/project/models"
 )
 
+// listCachePrefix/countCachePrefix namespace the List/Count result cache
+// separately from the single-object id/name keys, so the whole namespace can
+// be swept with one Keys/Delete pattern sweep whenever the underlying data
+// changes, without touching the longer-lived object cache.
+const (
+	listCachePrefix  = "project:list:"
+	countCachePrefix = "project:count:"
+)
+
+// projectInvalidateChannel is the redis pub/sub channel every replica's L1
+// subscribes to, so evicting a key on one replica evicts it everywhere
+// instead of only there until the L1 TTL expires.
+const projectInvalidateChannel = "harbor:cache:invalidate:project"
+
+// l1Capacity bounds the number of projects the L1 layer keeps resident.
+const l1Capacity = 4096
+
 var _ CachedManager = &Manager{}
 
 // CachedManager is the interface combines raw resource Manager and cached Manager for better extension.
@@ -38,6 +62,10 @@ type CachedManager interface {
 	project.Manager
 	// Manager is the common interface for resource cache.
 	cached.Manager
+	// InvalidateByTag flushes every cache entry associated with tag, e.g.
+	// "owner:<uid>" or "registry:<id>", without the caller having to know
+	// the individual project IDs/names the tag covers.
+	InvalidateByTag(ctx context.Context, tag string) error
 }
 
 // Manager is the cached Manager implemented by redis.
@@ -48,36 +76,218 @@ type Manager struct {
 	client func() libcache.Cache
 	// keyBuilder builds cache object key.
 	keyBuilder *cached.ObjectKey
-	// lifetime is the cache life time.
+	// lifetime is the cache life time for single project objects.
 	lifetime time.Duration
+	// listLifetime is the cache life time for List/Count results, kept
+	// separate since freshness expectations for a listing differ from a
+	// single object read.
+	listLifetime time.Duration
+	// l1 is a bounded, short-lived in-process cache that sits in front of
+	// the redis (L2) client so same-replica requests for a hot project
+	// never leave the process.
+	l1 libcache.Cache
+	// l1Lifetime is how long an entry is trusted in L1 before it's
+	// re-fetched from L2, independent of cross-node invalidation.
+	l1Lifetime time.Duration
+	// sf collapses concurrent L1/L2 misses for the same key into a single
+	// delegator call, so a cache-stampede on a just-expired project costs
+	// one DAO call rather than one per waiting request.
+	sf singleflight.Group
+}
+
+// cacheNamespace is the name this Manager asks cm for its Cache under, so
+// operators can route project entries to a dedicated backend/TTL/prefix
+// independently of every other cached resource.
+const cacheNamespace = "project"
+
+// NewManager returns the redis cache Manager. cm hands out the namespaced
+// Cache this Manager reads/writes through, instead of every cached manager
+// reaching for the single libcache.Default() global.
+func NewManager(m project.Manager, cm libcache.Manager) *Manager {
+	mgr := &Manager{
+		delegator:    m,
+		client:       func() libcache.Cache { return cm.Cache(cacheNamespace) },
+		keyBuilder:   cached.NewObjectKey(cached.ResourceTypeProject),
+		lifetime:     time.Duration(config.CacheExpireHours()) * time.Hour,
+		listLifetime: time.Duration(config.ListCacheExpireSeconds()) * time.Second,
+		l1:           libcache.NewMemory(l1Capacity, time.Duration(config.ProjectL1CacheExpireSeconds())*time.Second),
+		l1Lifetime:   time.Duration(config.ProjectL1CacheExpireSeconds()) * time.Second,
+	}
+
+	if n, ok := mgr.client().(libcache.Notifier); ok {
+		go mgr.subscribeInvalidations(n)
+	} else {
+		log.Debugf("project cache backend does not support pub/sub, L1 will only evict via its own TTL")
+	}
+
+	return mgr
+}
+
+// subscribeInvalidations evicts from L1 whenever any replica publishes a key
+// invalidation on projectInvalidateChannel, keeping every replica's L1
+// coherent with the shared L2. It blocks and is meant to run in its own
+// goroutine for the Manager's lifetime.
+func (m *Manager) subscribeInvalidations(n libcache.Notifier) {
+	err := n.Subscribe(context.Background(), projectInvalidateChannel, func(key string) {
+		if err := m.l1.Delete(context.Background(), key); err != nil {
+			log.Debugf("evict project L1 cache key %s error: %v", key, err)
+		}
+	})
+	if err != nil {
+		log.Errorf("subscribe to project cache invalidation channel error: %v", err)
+	}
 }
 
-// NewManager returns the redis cache Manager.
-func NewManager(m project.Manager) *Manager {
-	return &Manager{
-		delegator:  m,
-		client:     func() libcache.Cache { return libcache.Default() },
-		keyBuilder: cached.NewObjectKey(cached.ResourceTypeProject),
-		lifetime:   time.Duration(config.CacheExpireHours()) * time.Hour,
+// invalidateL1 evicts key from the local L1 and publishes it so every other
+// replica's L1 evicts it too.
+func (m *Manager) invalidateL1(ctx context.Context, key string) {
+	if err := m.l1.Delete(ctx, key); err != nil {
+		log.Debugf("evict project L1 cache key %s error: %v", key, err)
+	}
+
+	if n, ok := m.client().(libcache.Notifier); ok {
+		if err := n.Publish(ctx, projectInvalidateChannel, key); err != nil {
+			log.Debugf("publish project cache invalidation for key %s error: %v", key, err)
+		}
 	}
 }
 
 func (m *Manager) Create(ctx context.Context, project *models.Project) (int64, error) {
-	return m.delegator.Create(ctx, project)
+	id, err := m.delegator.Create(ctx, project)
+	if err != nil {
+		return id, err
+	}
+	m.clearListCache(ctx)
+	return id, nil
 }
 
 func (m *Manager) Count(ctx context.Context, query *q.Query) (total int64, err error) {
-	return m.delegator.Count(ctx, query)
+	if query != nil && query.NoCache {
+		return m.delegator.Count(ctx, query)
+	}
+
+	key, err := listCacheKey(countCachePrefix, query)
+	if err != nil {
+		log.Debugf("build project count cache key error: %v, will query from database.", err)
+		return m.delegator.Count(ctx, query)
+	}
+
+	if err = m.client().Fetch(ctx, key, &total); err == nil {
+		return total, nil
+	}
+
+	total, err = m.delegator.Count(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = m.client().Save(ctx, key, total, m.listLifetime); err != nil {
+		log.Debugf("save project count to cache error: %v", err)
+	}
+
+	return total, nil
 }
 
 func (m *Manager) List(ctx context.Context, query *q.Query) ([]*models.Project, error) {
-	return m.delegator.List(ctx, query)
+	if query != nil && query.NoCache {
+		return m.delegator.List(ctx, query)
+	}
+
+	key, err := listCacheKey(listCachePrefix, query)
+	if err != nil {
+		log.Debugf("build project list cache key error: %v, will query from database.", err)
+		return m.delegator.List(ctx, query)
+	}
+
+	var projects []*models.Project
+	if err = m.client().Fetch(ctx, key, &projects); err == nil {
+		return projects, nil
+	}
+
+	projects, err = m.delegator.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = m.client().Save(ctx, key, projects, m.listLifetime); err != nil {
+		log.Debugf("save project list to cache error: %v", err)
+	}
+
+	return projects, nil
+}
+
+// listCacheKey derives a stable cache key for query by hashing its sorted
+// keywords together with its pagination and sorting, so the same logical
+// query always maps to the same key regardless of map iteration order.
+func listCacheKey(prefix string, query *q.Query) (string, error) {
+	query = q.MustClone(query)
+
+	keys := make([]string, 0, len(query.Keywords))
+	for k := range query.Keywords {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sorted := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		sorted[k] = query.Keywords[k]
+	}
+
+	raw, err := json.Marshal(struct {
+		Keywords   map[string]interface{}
+		PageNumber int64
+		PageSize   int64
+		Sorts      []*q.Sort
+		Sorting    string
+	}{
+		Keywords:   sorted,
+		PageNumber: query.PageNumber,
+		PageSize:   query.PageSize,
+		Sorts:      query.Sorts,
+		Sorting:    query.Sorting,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha1.Sum(raw)
+	return prefix + hex.EncodeToString(sum[:]), nil
+}
+
+// clearListCache invalidates every cached List/Count result by sweeping the
+// list/count namespace, mirroring the clearProjectsPattern/
+// clearProjectsAllGetAll pattern: any Create/Delete/mutation can shift which
+// rows satisfy an existing query, so per-key invalidation isn't possible and
+// the whole namespace is swept instead.
+func (m *Manager) clearListCache(ctx context.Context) {
+	for _, prefix := range []string{listCachePrefix, countCachePrefix} {
+		keys, err := m.client().Keys(ctx, prefix)
+		if err != nil {
+			log.Errorf("scan project cache keys %s* error: %v", prefix, err)
+			continue
+		}
+		for _, key := range keys {
+			if err = retry.Retry(func() error { return m.client().Delete(ctx, key) }); err != nil {
+				log.Errorf("delete project cache key %s error: %v", key, err)
+			}
+		}
+	}
 }
 
 func (m *Manager) ListRoles(ctx context.Context, projectID int64, userID int, groupIDs ...int) ([]int, error) {
 	return m.delegator.ListRoles(ctx, projectID, userID, groupIDs...)
 }
 
+// Delete removes the project and, once it's gone, invalidates every cache
+// entry it logically affects: its own id/name keys, the List/Count
+// namespace, and, via cached.DefaultInvalidationHub, whatever other cached
+// managers (repositories, artifacts, quotas, robots, webhooks, members, ...)
+// keyed something by this project's ID.
+//
+// Ordering contract: DAO delete first, then cross-cache fanout. A partial
+// fanout failure must never leave a live cache pointing at a row that's
+// already gone from the database, so the delete is committed before any
+// cache is touched.
 func (m *Manager) Delete(ctx context.Context, id int64) error {
 	p, err := m.Get(ctx, id)
 	if err != nil {
@@ -90,56 +300,141 @@ func (m *Manager) Delete(ctx context.Context, id int64) error {
 	}
 	// clean cache
 	m.cleanUp(ctx, p)
+	m.clearListCache(ctx)
+
+	if err := cached.DefaultInvalidationHub.NotifyProjectDeleted(ctx, id); err != nil {
+		// the project row is already gone; a straggling cache entry in
+		// another resource's cache will self-heal on its own TTL, so this
+		// is logged rather than failing the delete.
+		log.Errorf("cross-cache invalidation for deleted project %d error: %v", id, err)
+	}
+
 	return nil
 }
 
 func (m *Manager) Get(ctx context.Context, idOrName interface{}) (*models.Project, error) {
-	var (
-		key string
-		err error
-	)
+	key, err := m.cacheKey(idOrName)
+	if err != nil {
+		return nil, err
+	}
 
-	id, name, err := utils.ParseProjectIDOrName(idOrName)
+	p := &models.Project{}
+	if err = m.l1.Fetch(ctx, key, p); err == nil {
+		return p, nil
+	}
+
+	// singleflight collapses concurrent L1/L2 misses for the same key into
+	// one call, so a stampede of requests for a just-expired project only
+	// costs one DAO call instead of one per request.
+	v, err, _ := m.sf.Do(key, func() (interface{}, error) {
+		return m.getAndCache(ctx, idOrName, key)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if id != 0 {
-		key, err = m.keyBuilder.Format("id", id)
-		if err != nil {
-			return nil, err
-		}
+	return v.(*models.Project), nil
+}
+
+// cacheKey builds the L1/L2 cache key for idOrName.
+func (m *Manager) cacheKey(idOrName interface{}) (string, error) {
+	id, name, err := utils.ParseProjectIDOrName(idOrName)
+	if err != nil {
+		return "", err
 	}
 
-	if name != "" {
-		key, err = m.keyBuilder.Format("name", name)
-		if err != nil {
-			return nil, err
-		}
+	if id != 0 {
+		return m.keyBuilder.Format("id", id)
 	}
+	return m.keyBuilder.Format("name", name)
+}
 
+// getAndCache resolves idOrName through L2 (redis) and, on an L2 miss,
+// through the DAO, caching the result back into both layers on the way out.
+func (m *Manager) getAndCache(ctx context.Context, idOrName interface{}, key string) (*models.Project, error) {
 	p := &models.Project{}
-	if err = m.client().Fetch(ctx, key, p); err == nil {
+	if err := m.client().Fetch(ctx, key, p); err == nil {
+		m.saveL1(ctx, key, p)
 		return p, nil
 	}
+	log.Debugf("get project %v from cache error, will query from database.", idOrName)
 
-	log.Debugf("get project %v from cache error: %v, will query from database.", idOrName, err)
-
-	p, err = m.delegator.Get(ctx, idOrName)
+	p, err := m.delegator.Get(ctx, idOrName)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = m.client().Save(ctx, key, p, m.lifetime); err != nil {
+	if err = m.client().SaveWithTags(ctx, key, p, tagsFor(p), m.lifetime); err != nil {
 		// log error if save to cache failed
 		log.Debugf("save project %s to cache error: %v", p.Name, err)
 	}
+	m.saveL1(ctx, key, p)
 
 	return p, nil
 }
 
-// cleanUp cleans up data in cache.
+func (m *Manager) saveL1(ctx context.Context, key string, p *models.Project) {
+	if err := m.l1.Save(ctx, key, p, m.l1Lifetime); err != nil {
+		log.Debugf("save project %s to L1 cache error: %v", p.Name, err)
+	}
+}
+
+// InvalidateByTag flushes every project cache entry tagged with tag, e.g.
+// "owner:<uid>", "registry:<id>" or "project:<id>". It's the hook cross-
+// cutting events such as an owner rename, a quota update or a member-role
+// change should call instead of enumerating the projects they affect.
+//
+// It evicts and publishes each invalidated key through the same L1-evict-
+// and-publish path Delete uses, so every replica's L1 drops the stale
+// project immediately instead of serving it for up to l1Lifetime.
+func (m *Manager) InvalidateByTag(ctx context.Context, tag string) error {
+	keys, err := m.client().InvalidateTag(ctx, tag)
+	if err != nil {
+		log.Errorf("invalidate project cache tag %s error: %v", tag, err)
+		return err
+	}
+
+	for _, key := range keys {
+		m.invalidateL1(ctx, key)
+	}
+
+	return nil
+}
+
+// projectTag returns the tag a cached project is uniquely indexed under.
+// Unlike owner/registry, this tag is never shared by another project, so
+// once p is gone the whole tag can be invalidated outright.
+func projectTag(p *models.Project) string {
+	return fmt.Sprintf("project:%d", p.ProjectID)
+}
+
+// sharedTagsFor returns the tags p is indexed under that other projects may
+// also be indexed under (same owner/registry), so retiring p must retract
+// its own membership rather than invalidating the tag wholesale.
+func sharedTagsFor(p *models.Project) []string {
+	var tags []string
+	if p.OwnerID > 0 {
+		tags = append(tags, fmt.Sprintf("owner:%d", p.OwnerID))
+	}
+	if p.RegistryID > 0 {
+		tags = append(tags, fmt.Sprintf("registry:%d", p.RegistryID))
+	}
+	return tags
+}
+
+// tagsFor returns every tag a cached project should be indexed under so it
+// can be found again by InvalidateByTag.
+func tagsFor(p *models.Project) []string {
+	return append([]string{projectTag(p)}, sharedTagsFor(p)...)
+}
+
+// cleanUp cleans up data in cache: the project's own id/name object keys,
+// its own single-project project:<id> tag, and its membership in the shared
+// owner/registry tags. The shared tags themselves are left intact since
+// other live projects may still be indexed under them.
 func (m *Manager) cleanUp(ctx context.Context, p *models.Project) {
+	var keys []string
+
 	// clean index by id
 	idIdx, err := m.keyBuilder.Format("id", p.ProjectID)
 	if err != nil {
@@ -149,6 +444,8 @@ func (m *Manager) cleanUp(ctx context.Context, p *models.Project) {
 		if err = retry.Retry(func() error { return m.client().Delete(ctx, idIdx) }); err != nil {
 			log.Errorf("delete project cache key %s error: %v", idIdx, err)
 		}
+		m.invalidateL1(ctx, idIdx)
+		keys = append(keys, idIdx)
 	}
 
 	// clean index by name
@@ -159,6 +456,26 @@ func (m *Manager) cleanUp(ctx context.Context, p *models.Project) {
 		if err = retry.Retry(func() error { return m.client().Delete(ctx, nameIdx) }); err != nil {
 			log.Errorf("delete project cache key %s error: %v", nameIdx, err)
 		}
+		m.invalidateL1(ctx, nameIdx)
+		keys = append(keys, nameIdx)
+	}
+
+	// project:<id> only ever has this project's own keys as members, so
+	// drop it entirely instead of leaving an orphaned single-project set
+	// behind in redis forever.
+	if _, err := m.client().InvalidateTag(ctx, projectTag(p)); err != nil {
+		log.Errorf("invalidate project cache tag %s error: %v", projectTag(p), err)
+	}
+
+	// owner/registry tags are shared with every other project under the
+	// same owner/registry, so only retract this project's own keys from
+	// them rather than invalidating the whole tag.
+	if shared := sharedTagsFor(p); len(shared) > 0 {
+		for _, key := range keys {
+			if err := m.client().Untag(ctx, key, shared); err != nil {
+				log.Errorf("untag project cache key %s error: %v", key, err)
+			}
+		}
 	}
 }
 
@@ -166,20 +483,47 @@ func (m *Manager) ResourceType(ctx context.Context) string {
 	return cached.ResourceTypeProject
 }
 
+// CountCache reports the number of distinct cache entries for this resource
+// across both L1 and L2, so an operator inspecting cache size via this hook
+// sees the L1 layer introduced in front of L2 rather than just the L2 count.
 func (m *Manager) CountCache(ctx context.Context) (int64, error) {
 	// prefix is resource type
-	keys, err := m.client().Keys(ctx, m.ResourceType(ctx))
+	l2Keys, err := m.client().Keys(ctx, m.ResourceType(ctx))
 	if err != nil {
 		return 0, err
 	}
 
-	return int64(len(keys)), nil
+	l1Keys, err := m.l1.Keys(ctx, m.ResourceType(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	distinct := make(map[string]struct{}, len(l2Keys)+len(l1Keys))
+	for _, key := range l2Keys {
+		distinct[key] = struct{}{}
+	}
+	for _, key := range l1Keys {
+		distinct[key] = struct{}{}
+	}
+
+	return int64(len(distinct)), nil
 }
 
+// DeleteCache deletes key from L2 and, like cleanUp/InvalidateByTag, evicts
+// it from L1 and publishes the eviction so every replica's L1 drops it too,
+// instead of serving it stale for up to l1Lifetime.
 func (m *Manager) DeleteCache(ctx context.Context, key string) error {
-	return m.client().Delete(ctx, key)
+	if err := m.client().Delete(ctx, key); err != nil {
+		return err
+	}
+	m.invalidateL1(ctx, key)
+	return nil
 }
 
+// FlushAll deletes every cache entry for this resource from L2 and, for each
+// one, evicts and publishes it the same way DeleteCache/cleanUp do, so a
+// cache flush actually clears every replica's L1 instead of leaving it
+// serving stale data until l1Lifetime expires.
 func (m *Manager) FlushAll(ctx context.Context) error {
 	// prefix is resource type
 	keys, err := m.client().Keys(ctx, m.ResourceType(ctx))
@@ -191,7 +535,9 @@ func (m *Manager) FlushAll(ctx context.Context) error {
 	for _, key := range keys {
 		if err = m.client().Delete(ctx, key); err != nil {
 			errs = append(errs, err)
+			continue
 		}
+		m.invalidateL1(ctx, key)
 	}
 
 	if errs.Len() > 0 {
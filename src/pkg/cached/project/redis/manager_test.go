@@ -0,0 +1,60 @@
+// Copyright Project Harbor Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/goharbor/harbor/src/lib/q"
+)
+
+func TestListCacheKeyStableForEquivalentQueries(t *testing.T) {
+	a := &q.Query{Keywords: map[string]interface{}{"b": 2, "a": 1}}
+	b := &q.Query{Keywords: map[string]interface{}{"a": 1, "b": 2}}
+
+	keyA, err := listCacheKey(listCachePrefix, a)
+	if err != nil {
+		t.Fatalf("listCacheKey(a) error: %v", err)
+	}
+	keyB, err := listCacheKey(listCachePrefix, b)
+	if err != nil {
+		t.Fatalf("listCacheKey(b) error: %v", err)
+	}
+
+	if keyA != keyB {
+		t.Errorf("expected queries differing only in keyword insertion order to hash to the same key, got %q != %q", keyA, keyB)
+	}
+}
+
+func TestListCacheKeyDiffersBySorts(t *testing.T) {
+	base := &q.Query{Keywords: map[string]interface{}{"a": 1}}
+	sorted := &q.Query{
+		Keywords: map[string]interface{}{"a": 1},
+		Sorts:    []*q.Sort{{Key: "name", DESC: true}},
+	}
+
+	keyBase, err := listCacheKey(listCachePrefix, base)
+	if err != nil {
+		t.Fatalf("listCacheKey(base) error: %v", err)
+	}
+	keySorted, err := listCacheKey(listCachePrefix, sorted)
+	if err != nil {
+		t.Fatalf("listCacheKey(sorted) error: %v", err)
+	}
+
+	if keyBase == keySorted {
+		t.Errorf("queries differing only in Sorts must not hash to the same cache key, got %q for both", keyBase)
+	}
+}